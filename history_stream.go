@@ -0,0 +1,195 @@
+package zabbix
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HistoryPoint is a single value returned by history.get.
+type HistoryPoint = History
+
+// defaultHistoryWindow is the time span covered by a single history.get
+// call when the caller doesn't set HistoryStreamParams.Window.
+const defaultHistoryWindow = 24 * time.Hour
+
+// defaultMaxInFlight is how many windows are fetched concurrently when
+// HistoryStreamParams.MaxInFlight is zero.
+const defaultMaxInFlight = 4
+
+// HistoryStreamParams describes a history.get query to run over
+// StreamHistory, split into windows so it never has to hold the whole
+// result set in memory at once.
+type HistoryStreamParams struct {
+	// ItemIDs restricts the query to these items (required).
+	ItemIDs []string
+	// History is the Zabbix history value type (0=float, 1=string,
+	// 2=log, 3=unsigned, 4=text) to query. Required: the zero value (0)
+	// is itself a valid, meaningful type (float), not a "default", so
+	// callers must set this explicitly for their item's value type.
+	History int
+	// TimeFrom and TimeTill bound the overall query, as Unix timestamps.
+	TimeFrom, TimeTill int64
+	// Window is the time span fetched per history.get call. Defaults to
+	// defaultHistoryWindow.
+	Window time.Duration
+	// MaxInFlight caps how many windows are requested concurrently.
+	// Defaults to defaultMaxInFlight.
+	MaxInFlight int
+}
+
+func (p HistoryStreamParams) withDefaults() HistoryStreamParams {
+	if p.Window <= 0 {
+		p.Window = defaultHistoryWindow
+	}
+	if p.MaxInFlight <= 0 {
+		p.MaxInFlight = defaultMaxInFlight
+	}
+	return p
+}
+
+// StreamHistory runs a history.get query in TimeFrom/TimeTill windows and
+// streams the resulting points over the returned channel, closing it when
+// every window has been fetched or ctx is cancelled. The error channel
+// receives at most one error (the first encountered) and is closed
+// alongside the point channel.
+func (api *API) StreamHistory(ctx context.Context, params HistoryStreamParams) (<-chan HistoryPoint, <-chan error) {
+	params = params.withDefaults()
+
+	points := make(chan HistoryPoint)
+	errs := make(chan error, 1)
+
+	windows := historyWindows(params.TimeFrom, params.TimeTill, params.Window)
+
+	go func() {
+		defer close(points)
+		defer close(errs)
+
+		sem := make(chan struct{}, params.MaxInFlight)
+		var wg sync.WaitGroup
+		var once sync.Once
+		failCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		fail := func(err error) {
+			once.Do(func() {
+				errs <- err
+				cancel()
+			})
+		}
+
+		for _, w := range windows {
+			select {
+			case <-failCtx.Done():
+			case sem <- struct{}{}:
+				wg.Add(1)
+				go func(w historyWindow) {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					result, err := api.GetHistories(map[string]interface{}{
+						"itemids":   params.ItemIDs,
+						"history":   params.History,
+						"time_from": w.from,
+						"time_till": w.till,
+						"sortfield": "clock",
+						"sortorder": "ASC",
+					})
+					if err != nil {
+						fail(err)
+						return
+					}
+
+					for _, point := range result {
+						select {
+						case points <- point:
+						case <-failCtx.Done():
+							return
+						}
+					}
+				}(w)
+			}
+		}
+
+		wg.Wait()
+	}()
+
+	return points, errs
+}
+
+type historyWindow struct {
+	from, till int64
+}
+
+// historyWindows splits [from, till] into consecutive windows of size at
+// most step seconds.
+func historyWindows(from, till int64, window time.Duration) []historyWindow {
+	step := int64(window.Seconds())
+	if step <= 0 {
+		step = int64(defaultHistoryWindow.Seconds())
+	}
+
+	var windows []historyWindow
+	for start := from; start < till; start += step {
+		end := start + step
+		if end > till {
+			end = till
+		}
+		windows = append(windows, historyWindow{from: start, till: end})
+	}
+	return windows
+}
+
+// HistoryIterator offers pull-style access to a StreamHistory call for
+// callers that prefer Next()/Value()/Err() over reading channels directly.
+type HistoryIterator struct {
+	points <-chan HistoryPoint
+	errs   <-chan error
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	current HistoryPoint
+	err     error
+}
+
+// NewHistoryIterator starts a StreamHistory query and returns an iterator
+// over its results.
+func NewHistoryIterator(ctx context.Context, api *API, params HistoryStreamParams) *HistoryIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	points, errs := api.StreamHistory(ctx, params)
+	return &HistoryIterator{points: points, errs: errs, ctx: ctx, cancel: cancel}
+}
+
+// Next advances the iterator, returning false when the stream is
+// exhausted, an error occurred, or Close has been called. Check Err after
+// Next returns false.
+func (it *HistoryIterator) Next() bool {
+	if it.ctx.Err() != nil {
+		return false
+	}
+	point, ok := <-it.points
+	if !ok {
+		if it.err == nil {
+			// Drain the error channel; it's closed alongside points.
+			it.err = <-it.errs
+		}
+		return false
+	}
+	it.current = point
+	return true
+}
+
+// Value returns the point most recently yielded by Next.
+func (it *HistoryIterator) Value() HistoryPoint {
+	return it.current
+}
+
+// Err returns the first error encountered while streaming, if any.
+func (it *HistoryIterator) Err() error {
+	return it.err
+}
+
+// Close stops the underlying stream early. Safe to call multiple times.
+func (it *HistoryIterator) Close() {
+	it.cancel()
+}
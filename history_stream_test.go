@@ -0,0 +1,148 @@
+package zabbix
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamHistoryTwoWindows(t *testing.T) {
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		if req.Method != "history.get" {
+			t.Fatalf("unexpected method %q", req.Method)
+		}
+		params, _ := req.Params.(map[string]interface{})
+		from := int64(params["time_from"].(float64))
+		writeJSONResponse(t, w, req.ID, []History{{ItemID: "1", Clock: strconv.FormatInt(from, 10)}}, nil)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	points, errs := api.StreamHistory(context.Background(), HistoryStreamParams{
+		ItemIDs:  []string{"1"},
+		History:  0,
+		TimeFrom: 0,
+		TimeTill: 7200,
+		Window:   time.Hour,
+	})
+
+	var got []string
+	for p := range points {
+		got = append(got, p.Clock)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(got)
+	want := []string{"0", "3600"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("got clocks %v, want %v", got, want)
+	}
+}
+
+// TestStreamHistoryRespectsMaxInFlight proves windows are actually fetched
+// concurrently (not serialized) up to MaxInFlight, by holding every
+// in-flight request open until MaxInFlight of them have arrived at once.
+func TestStreamHistoryRespectsMaxInFlight(t *testing.T) {
+	const maxInFlight = 2
+	const windowCount = 5
+
+	var current, maxObserved atomic.Int32
+	release := make(chan struct{})
+	var closeOnce int32
+
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		n := current.Add(1)
+		for {
+			old := maxObserved.Load()
+			if n <= old || maxObserved.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		if n >= maxInFlight && atomic.CompareAndSwapInt32(&closeOnce, 0, 1) {
+			close(release)
+		}
+		<-release
+		current.Add(-1)
+		writeJSONResponse(t, w, req.ID, []History{}, nil)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	points, errs := api.StreamHistory(context.Background(), HistoryStreamParams{
+		ItemIDs:     []string{"1"},
+		History:     0,
+		TimeFrom:    0,
+		TimeTill:    int64(windowCount) * 3600,
+		Window:      time.Hour,
+		MaxInFlight: maxInFlight,
+	})
+
+	for range points {
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := maxObserved.Load(); got != maxInFlight {
+		t.Fatalf("max concurrent history.get calls = %d, want %d", got, maxInFlight)
+	}
+}
+
+// TestHistoryIteratorCloseMidStream checks that closing the iterator while
+// a window is still in flight makes Next stop yielding, instead of
+// delivering the in-flight window's point or hanging.
+func TestHistoryIteratorCloseMidStream(t *testing.T) {
+	served := make(chan struct{}, 1)
+	block := make(chan struct{})
+
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		select {
+		case served <- struct{}{}:
+		default:
+		}
+		<-block
+		writeJSONResponse(t, w, req.ID, []History{{ItemID: "1", Clock: "1"}}, nil)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	it := NewHistoryIterator(context.Background(), api, HistoryStreamParams{
+		ItemIDs:     []string{"1"},
+		History:     0,
+		TimeFrom:    0,
+		TimeTill:    7200,
+		Window:      time.Hour,
+		MaxInFlight: 1,
+	})
+
+	<-served // the first window's request is now blocked in the handler
+	it.Close()
+	close(block) // let it finish now that Close has already run
+
+	done := make(chan bool, 1)
+	go func() { done <- it.Next() }()
+
+	select {
+	case ok := <-done:
+		if ok {
+			t.Fatal("expected Next to return false after Close, got a value")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Next did not return after Close")
+	}
+}
@@ -0,0 +1,128 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestConcurrentLoginIsRaceFree calls Login from many goroutines sharing
+// one *API (no SessionStore configured) and checks the shared auth state
+// ends up holding the issued token. Login serializes on loginMu but each
+// call still performs its own user.login. Run with -race to catch
+// regressions on the shared auth state.
+func TestConcurrentLoginIsRaceFree(t *testing.T) {
+	var loginCalls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JsonRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		resp := JsonResponse{Jsonrpc: "2.0", ID: req.ID}
+		switch req.Method {
+		case "apiinfo.version":
+			resp.Result = "6.0.0"
+		case "user.login":
+			atomic.AddInt32(&loginCalls, 1)
+			resp.Result = "sometoken"
+		default:
+			t.Fatalf("unexpected method %q", req.Method)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := api.Login(); err != nil {
+				t.Errorf("Login: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	token, _ := api.authSnapshot()
+	if token != "sometoken" {
+		t.Fatalf("auth token = %q, want sometoken", token)
+	}
+}
+
+// TestConcurrentSessionExpiryTriggersSingleReLogin simulates many
+// in-flight requests hitting a "session terminated" error at once: only
+// one of them should call user.login to refresh the shared session.
+func TestConcurrentSessionExpiryTriggersSingleReLogin(t *testing.T) {
+	var loginCalls int32
+	var expiredOnce sync.Once
+	expired := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JsonRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		resp := JsonResponse{Jsonrpc: "2.0", ID: req.ID}
+		switch req.Method {
+		case "apiinfo.version":
+			resp.Result = "6.0.0"
+		case "user.login":
+			n := atomic.AddInt32(&loginCalls, 1)
+			resp.Result = "token-v2"
+			if n == 1 {
+				expiredOnce.Do(func() { close(expired) })
+			}
+		case "some.method":
+			if req.Auth == "token-v1" {
+				resp.Error = ZbxError{Code: -32602, Message: "Invalid params.", Data: "Session terminated, re-login, please."}
+			} else {
+				resp.Result = "ok"
+			}
+		default:
+			t.Fatalf("unexpected method %q", req.Method)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{MaxRetries: 1, Backoff: noBackoff()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	api.setAuth("token-v1")
+
+	const goroutines = 20
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			resp, err := api.Request("some.method", nil)
+			if err != nil {
+				t.Errorf("Request: %v", err)
+				return
+			}
+			if resp.Result != "ok" {
+				t.Errorf("Result = %v, want ok", resp.Result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&loginCalls); got != 1 {
+		t.Fatalf("user.login calls = %d, want exactly 1", got)
+	}
+}
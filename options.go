@@ -0,0 +1,175 @@
+package zabbix
+
+import (
+	"context"
+	"crypto/tls"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ClientOptions configures a client created with NewClient. The zero value
+// is a usable, conservative default: a plain *http.Client, no timeout
+// beyond whatever the HTTPClient itself enforces, no retries and no rate
+// limiting.
+type ClientOptions struct {
+	// HTTPClient is used to perform requests. If nil, a new *http.Client
+	// is created, optionally configured with TLSConfig/InsecureSkipVerify.
+	HTTPClient *http.Client
+
+	// Timeout bounds a single Request/RequestContext call, including all
+	// retries. Zero means no additional timeout is applied.
+	Timeout time.Duration
+
+	// MaxRetries is the number of additional attempts made after a
+	// transient failure. Zero means requests are never retried.
+	MaxRetries int
+
+	// Backoff controls the delay between retry attempts. If nil, a
+	// default exponential backoff with jitter is used.
+	Backoff Backoff
+
+	// RateLimit, if set, is consulted before every attempt (including the
+	// first) so bulk operations don't overwhelm a busy Zabbix server.
+	RateLimit RateLimiter
+
+	// TLSConfig is applied to the HTTP transport when HTTPClient is nil.
+	TLSConfig *tls.Config
+
+	// InsecureSkipVerify disables TLS certificate verification when
+	// HTTPClient is nil. Prefer TLSConfig for anything beyond quick
+	// local testing.
+	InsecureSkipVerify bool
+
+	// SessionStore, if set, lets Login reuse a cached auth token instead
+	// of calling user.login on every process start.
+	SessionStore SessionStore
+}
+
+// withDefaults returns a copy of opts with nil fields replaced by their
+// defaults.
+func (opts ClientOptions) withDefaults() ClientOptions {
+	if opts.Backoff == nil {
+		opts.Backoff = DefaultBackoff
+	}
+	return opts
+}
+
+// buildHTTPClient returns opts.HTTPClient if set, otherwise constructs one
+// honouring TLSConfig/InsecureSkipVerify.
+func (opts ClientOptions) buildHTTPClient() (*http.Client, error) {
+	if opts.HTTPClient != nil {
+		return opts.HTTPClient, nil
+	}
+
+	tlsConfig := opts.TLSConfig
+	if opts.InsecureSkipVerify {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if tlsConfig == nil {
+		return &http.Client{}, nil
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
+}
+
+// RateLimiter throttles outgoing requests. Wait blocks until the caller is
+// allowed to proceed or ctx is done.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// NewTokenBucketLimiter returns a RateLimiter that allows ratePerSecond
+// requests per second on average, with bursts up to burst requests.
+func NewTokenBucketLimiter(ratePerSecond float64, burst int) RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucketLimiter{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+type tokenBucketLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		elapsed := now.Sub(l.last).Seconds()
+		l.last = now
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff computes how long to wait before a given retry attempt (1-based:
+// attempt 1 is the first retry after the initial try).
+type Backoff interface {
+	Wait(ctx context.Context, attempt int) error
+}
+
+// DefaultBackoff is an exponential backoff starting at 200ms, doubling
+// each attempt, capped at 10s, with up to 20% jitter to avoid synchronised
+// retries across goroutines/processes.
+var DefaultBackoff Backoff = ExponentialBackoff{Base: 200 * time.Millisecond, Max: 10 * time.Second}
+
+// ExponentialBackoff doubles Base on every attempt up to Max, adding
+// randomized jitter of up to +/-20%.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (b ExponentialBackoff) Wait(ctx context.Context, attempt int) error {
+	d := float64(b.Base) * math.Pow(2, float64(attempt-1))
+	if max := float64(b.Max); d > max {
+		d = max
+	}
+	jitter := d * 0.2 * (rand.Float64()*2 - 1)
+	d += jitter
+	if d < 0 {
+		d = 0
+	}
+
+	timer := time.NewTimer(time.Duration(d))
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,270 @@
+package zabbix
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// zbxdHeader is the 4-byte "ZBXD" magic plus the 0x01 protocol flag that
+// start every Zabbix trapper protocol message; an 8-byte little-endian
+// payload length follows it on the wire.
+var zbxdHeader = []byte("ZBXD\x01")
+
+// Metric is a single value pushed to a Zabbix server/proxy via the
+// trapper protocol, equivalent to one line of zabbix_sender input.
+type Metric struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Clock int64  `json:"clock,omitempty"`
+	NS    int64  `json:"ns,omitempty"`
+}
+
+// SenderOptions configures a Sender. The zero value dials addr directly
+// with no TLS and a 30s timeout.
+type SenderOptions struct {
+	// Timeout bounds dialing and each Send call. Defaults to 30s.
+	Timeout time.Duration
+
+	// TLSConfig, if set, is used to dial addr over TLS instead of plain
+	// TCP. You can reuse the *tls.Config built for the API's
+	// ClientOptions here if it only carries client certs/CA pool, to
+	// keep sender and JSON-RPC transport consistent. Note this is plain
+	// TLS, not Zabbix's PSK scheme: Go's crypto/tls has no TLS-PSK
+	// support, so a PSK-only trapper connection can't be configured
+	// through this field.
+	TLSConfig *tls.Config
+
+	// BatchSize is the number of metrics SendAsync buffers before
+	// flushing. Defaults to 250.
+	BatchSize int
+
+	// FlushInterval is the maximum time SendAsync holds buffered metrics
+	// before flushing, even if BatchSize hasn't been reached. Defaults
+	// to 1s.
+	FlushInterval time.Duration
+}
+
+func (opts SenderOptions) withDefaults() SenderOptions {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 250
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	return opts
+}
+
+// Sender pushes metrics to a Zabbix server or proxy trapper port (10051 by
+// default) using the binary sender protocol, without shelling out to
+// zabbix_sender.
+type Sender struct {
+	addr string
+	opts SenderOptions
+
+	mu        sync.Mutex
+	pending   []Metric
+	flushed   chan struct{}
+	stopped   chan struct{}
+	once      sync.Once
+	closeOnce sync.Once
+}
+
+// NewSender creates a Sender targeting addr (host:port, typically the
+// Zabbix server/proxy's trapper port 10051).
+func NewSender(addr string, opts SenderOptions) *Sender {
+	return &Sender{
+		addr:    addr,
+		opts:    opts.withDefaults(),
+		flushed: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+type senderRequest struct {
+	Request string   `json:"request"`
+	Data    []Metric `json:"data"`
+	Clock   int64    `json:"clock,omitempty"`
+}
+
+// SenderResponse is the parsed reply to a trapper request.
+type SenderResponse struct {
+	Response     string
+	Processed    int
+	Failed       int
+	Total        int
+	SecondsSpent float64
+}
+
+var senderInfoPattern = regexp.MustCompile(`processed:\s*(\d+);\s*failed:\s*(\d+);\s*total:\s*(\d+);\s*seconds spent:\s*([\d.]+)`)
+
+// Send opens a connection, pushes items in a single trapper request and
+// returns the server's processed/failed/total/seconds-spent summary.
+func (s *Sender) Send(items []Metric) (SenderResponse, error) {
+	payload, err := json.Marshal(senderRequest{Request: "sender data", Data: items})
+	if err != nil {
+		return SenderResponse{}, err
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return SenderResponse{}, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.opts.Timeout)); err != nil {
+		return SenderResponse{}, err
+	}
+
+	if err := writeZbxdMessage(conn, payload); err != nil {
+		return SenderResponse{}, err
+	}
+
+	respPayload, err := readZbxdMessage(conn)
+	if err != nil {
+		return SenderResponse{}, err
+	}
+
+	var raw struct {
+		Response string `json:"response"`
+		Info     string `json:"info"`
+	}
+	if err := json.Unmarshal(respPayload, &raw); err != nil {
+		return SenderResponse{}, err
+	}
+
+	response := SenderResponse{Response: raw.Response}
+	if m := senderInfoPattern.FindStringSubmatch(raw.Info); m != nil {
+		response.Processed, _ = strconv.Atoi(m[1])
+		response.Failed, _ = strconv.Atoi(m[2])
+		response.Total, _ = strconv.Atoi(m[3])
+		response.SecondsSpent, _ = strconv.ParseFloat(m[4], 64)
+	}
+
+	if raw.Response != "success" {
+		return response, fmt.Errorf("zabbix: sender request failed: %s", raw.Info)
+	}
+	return response, nil
+}
+
+func (s *Sender) dial() (net.Conn, error) {
+	dialer := net.Dialer{Timeout: s.opts.Timeout}
+	if s.opts.TLSConfig != nil {
+		return tls.DialWithDialer(&dialer, "tcp", s.addr, s.opts.TLSConfig)
+	}
+	return dialer.Dial("tcp", s.addr)
+}
+
+func writeZbxdMessage(conn net.Conn, payload []byte) error {
+	header := make([]byte, len(zbxdHeader)+8)
+	copy(header, zbxdHeader)
+	binary.LittleEndian.PutUint64(header[len(zbxdHeader):], uint64(len(payload)))
+
+	if _, err := conn.Write(header); err != nil {
+		return err
+	}
+	_, err := conn.Write(payload)
+	return err
+}
+
+func readZbxdMessage(conn net.Conn) ([]byte, error) {
+	header := make([]byte, len(zbxdHeader)+8)
+	if _, err := readFull(conn, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(zbxdHeader)], zbxdHeader) {
+		return nil, fmt.Errorf("zabbix: unexpected sender response header %x", header[:len(zbxdHeader)])
+	}
+
+	length := binary.LittleEndian.Uint64(header[len(zbxdHeader):])
+	payload := make([]byte, length)
+	if _, err := readFull(conn, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// SendAsync queues items for delivery and returns immediately; they are
+// flushed together with any other pending metrics once BatchSize items
+// have accumulated or FlushInterval has elapsed, whichever comes first.
+// Call Flush to force an immediate send, and Close to flush and stop the
+// background flusher.
+func (s *Sender) SendAsync(items ...Metric) {
+	s.mu.Lock()
+	s.pending = append(s.pending, items...)
+	full := len(s.pending) >= s.opts.BatchSize
+	s.mu.Unlock()
+
+	s.once.Do(s.startFlusher)
+
+	if full {
+		select {
+		case s.flushed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Sender) startFlusher() {
+	go func() {
+		ticker := time.NewTicker(s.opts.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Flush()
+			case <-s.flushed:
+				s.Flush()
+			case <-s.stopped:
+				s.Flush()
+				return
+			}
+		}
+	}()
+}
+
+// Flush sends any buffered metrics immediately. It is safe to call
+// concurrently with SendAsync.
+func (s *Sender) Flush() (SenderResponse, error) {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return SenderResponse{}, nil
+	}
+	return s.Send(batch)
+}
+
+// Close stops the background flusher started by SendAsync, flushing any
+// remaining buffered metrics first. Safe to call multiple times, or even
+// if SendAsync was never used.
+func (s *Sender) Close() error {
+	s.closeOnce.Do(func() { close(s.stopped) })
+	_, err := s.Flush()
+	return err
+}
@@ -0,0 +1,123 @@
+package zabbix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeTrapperServer accepts a single ZBXD-framed request, hands it to
+// handle, and writes back a ZBXD-framed JSON response built from whatever
+// handle returns.
+func fakeTrapperServer(t *testing.T, handle func(req senderRequest) (response, info string)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		payload, err := readZbxdMessage(conn)
+		if err != nil {
+			return
+		}
+
+		var req senderRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			return
+		}
+
+		respStatus, info := handle(req)
+		respPayload, _ := json.Marshal(map[string]string{
+			"response": respStatus,
+			"info":     info,
+		})
+		writeZbxdMessage(conn, respPayload)
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestSenderSendParsesResponse(t *testing.T) {
+	var gotRequest string
+	addr := fakeTrapperServer(t, func(req senderRequest) (string, string) {
+		gotRequest = req.Request
+		return "success", "processed: 2; failed: 0; total: 2; seconds spent: 0.001234"
+	})
+
+	sender := NewSender(addr, SenderOptions{Timeout: 2 * time.Second})
+	resp, err := sender.Send([]Metric{
+		{Host: "host-a", Key: "agent.ping", Value: "1"},
+		{Host: "host-a", Key: "agent.uptime", Value: "42"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if gotRequest != "sender data" {
+		t.Fatalf("request field = %q, want %q", gotRequest, "sender data")
+	}
+	if resp.Processed != 2 || resp.Failed != 0 || resp.Total != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if resp.SecondsSpent != 0.001234 {
+		t.Fatalf("SecondsSpent = %v, want 0.001234", resp.SecondsSpent)
+	}
+}
+
+func TestSenderSendReportsFailure(t *testing.T) {
+	addr := fakeTrapperServer(t, func(req senderRequest) (string, string) {
+		return "failed", "processed: 0; failed: 1; total: 1; seconds spent: 0.000100"
+	})
+
+	sender := NewSender(addr, SenderOptions{Timeout: 2 * time.Second})
+	if _, err := sender.Send([]Metric{{Host: "host-a", Key: "agent.ping", Value: "1"}}); err == nil {
+		t.Fatal("expected an error for a failed sender response")
+	}
+}
+
+func TestZbxdWireFraming(t *testing.T) {
+	var buf bytes.Buffer
+	payload := []byte(`{"request":"sender data","data":[]}`)
+
+	if err := writeZbxdMessage(fakeConn{&buf}, payload); err != nil {
+		t.Fatalf("writeZbxdMessage: %v", err)
+	}
+
+	got := buf.Bytes()
+	if !bytes.Equal(got[:5], zbxdHeader) {
+		t.Fatalf("header = %x, want %x", got[:5], zbxdHeader)
+	}
+
+	length := binary.LittleEndian.Uint64(got[5:13])
+	if int(length) != len(payload) {
+		t.Fatalf("encoded length = %d, want %d", length, len(payload))
+	}
+	if !bytes.Equal(got[13:], payload) {
+		t.Fatalf("payload = %q, want %q", got[13:], payload)
+	}
+}
+
+// fakeConn adapts a *bytes.Buffer to the subset of net.Conn that
+// writeZbxdMessage/readZbxdMessage use.
+type fakeConn struct {
+	*bytes.Buffer
+}
+
+func (fakeConn) Close() error                       { return nil }
+func (fakeConn) LocalAddr() net.Addr                { return nil }
+func (fakeConn) RemoteAddr() net.Addr               { return nil }
+func (fakeConn) SetDeadline(t time.Time) error      { return nil }
+func (fakeConn) SetReadDeadline(t time.Time) error  { return nil }
+func (fakeConn) SetWriteDeadline(t time.Time) error { return nil }
@@ -0,0 +1,123 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// SessionStore persists Zabbix auth tokens across process restarts so a
+// new API instance can resume an existing session instead of burning a
+// fresh row in Zabbix's sessions table on every startup.
+type SessionStore interface {
+	// Load returns the cached token for user, if any.
+	Load(user string) (token string, ok bool)
+	// Save stores token as the current session for user.
+	Save(user, token string) error
+	// Delete removes any cached token for user.
+	Delete(user string) error
+}
+
+// MemorySessionStore is a SessionStore backed by an in-process map. Useful
+// for sharing one session across multiple *API instances within the same
+// process, but it doesn't survive a restart.
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]string
+}
+
+// NewMemorySessionStore creates an empty MemorySessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]string)}
+}
+
+func (s *MemorySessionStore) Load(user string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.sessions[user]
+	return token, ok
+}
+
+func (s *MemorySessionStore) Save(user, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[user] = token
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, user)
+	return nil
+}
+
+// FileSessionStore is a SessionStore backed by a single JSON file on disk,
+// so sessions survive across process restarts (e.g. a cron job or CLI
+// invoked repeatedly).
+type FileSessionStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSessionStore creates a FileSessionStore persisting to path. The
+// file is created on first Save if it doesn't already exist.
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+func (s *FileSessionStore) Load(user string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return "", false
+	}
+	token, ok := sessions[user]
+	return token, ok
+}
+
+func (s *FileSessionStore) Save(user, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		sessions = make(map[string]string)
+	}
+	sessions[user] = token
+	return s.write(sessions)
+}
+
+func (s *FileSessionStore) Delete(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, err := s.read()
+	if err != nil {
+		return nil
+	}
+	delete(sessions, user)
+	return s.write(sessions)
+}
+
+func (s *FileSessionStore) read() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make(map[string]string)
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *FileSessionStore) write(sessions map[string]string) error {
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,113 @@
+package zabbix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// versionAwareLoginServer fakes apiinfo.version + user.login, recording
+// which login param name was used and whether the request carried an
+// Authorization header.
+func versionAwareLoginServer(t *testing.T, version string) (*httptest.Server, *string, *bool) {
+	t.Helper()
+
+	var gotParam string
+	var gotAuthHeader bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			gotAuthHeader = true
+		}
+
+		var req JsonRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+
+		resp := JsonResponse{Jsonrpc: "2.0", ID: req.ID}
+		switch req.Method {
+		case "apiinfo.version":
+			resp.Result = version
+		case "user.login":
+			params, _ := req.Params.(map[string]interface{})
+			if _, ok := params["username"]; ok {
+				gotParam = "username"
+			} else if _, ok := params["user"]; ok {
+				gotParam = "user"
+			}
+			resp.Result = "sometoken"
+		default:
+			resp.Result = "ok"
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &gotParam, &gotAuthHeader
+}
+
+func TestLoginUsesUserParamBelow54(t *testing.T) {
+	srv, gotParam, gotAuthHeader := versionAwareLoginServer(t, "5.2.0")
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := api.Login(); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if *gotParam != "user" {
+		t.Fatalf("login param = %q, want \"user\"", *gotParam)
+	}
+	if *gotAuthHeader {
+		t.Fatal("expected no Authorization header for a pre-5.4 server")
+	}
+}
+
+func TestLoginUsesUsernameParamFrom54(t *testing.T) {
+	srv, gotParam, gotAuthHeader := versionAwareLoginServer(t, "6.0.0")
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := api.Login(); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if *gotParam != "username" {
+		t.Fatalf("login param = %q, want \"username\"", *gotParam)
+	}
+	if *gotAuthHeader {
+		t.Fatal("expected auth to still travel in the JSON body below 6.4, not a header")
+	}
+}
+
+func TestRequestUsesAuthHeaderFrom64(t *testing.T) {
+	srv, gotParam, gotAuthHeader := versionAwareLoginServer(t, "6.4.1")
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := api.Login(); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if *gotParam != "username" {
+		t.Fatalf("login param = %q, want \"username\"", *gotParam)
+	}
+
+	// user.login itself predates any session, so it can't carry the
+	// session in a header; the switch only matters for requests made
+	// after a token/session is established.
+	if _, err := api.Request("some.method", nil); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if !*gotAuthHeader {
+		t.Fatal("expected a 6.4+ server to receive auth via the Authorization header")
+	}
+}
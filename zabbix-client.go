@@ -1,168 +1,340 @@
-package zabbix
-
-import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"net/http"
-)
-
-type API struct {
-	url        string
-	user       string
-	pass       string
-	auth       string
-	loggedin   bool
-	id         int
-	httpClient *http.Client
-}
-
-type JsonRequest struct {
-	Jsonrpc string      `json:"jsonrpc"`
-	Method  string      `json:"method"`
-	Params  interface{} `json:"params"`
-	Auth    string      `json:"auth,omitempty"`
-	ID      int         `json:"id"`
-}
-
-type JsonResponse struct {
-	Jsonrpc string      `json:"jsonrpc"`
-	Result  interface{} `json:"result"`
-	Error   ZbxError    `json:"error"`
-	ID      int         `json:"id"`
-}
-
-type ZbxError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    string `json:"data"`
-}
-
-func (z *ZbxError) Error() string {
-	return fmt.Sprintf("Error code: %d, message: %s, data: %s", z.Code, z.Message, z.Data)
-}
-
-// NewClient creates new client
-func NewClient(url, user, pass string) (*API, error) {
-	if url == "" || user == "" || pass == "" {
-		return nil, errors.New("you have to provide url, user name and password")
-	}
-	return &API{url, user, pass, "", false, 0, &http.Client{}}, nil
-}
-
-// Login user in Zabbix
-func (api *API) Login() (bool, error) {
-	params := make(map[string]string)
-	params["user"] = api.user
-	params["password"] = api.pass
-
-	response, err := api.Request("user.login", params)
-	if err != nil {
-		return false, err
-	}
-
-	// check response error code
-	if response.Error.Code != 0 {
-		return false, &response.Error
-	}
-
-	api.auth = response.Result.(string)
-	api.loggedin = true
-	return true, nil
-}
-
-// Logout user
-func (api *API) Logout() (bool, error) {
-	emptyParams := make(map[string]string)
-
-	response, err := api.Request("user.logout", emptyParams)
-	if err != nil {
-		return false, err
-	}
-
-	// check response error code
-	if response.Error.Code != 0 {
-		return false, &response.Error
-	}
-
-	api.loggedin = false
-	api.auth = ""
-	return true, nil
-}
-
-// GetHost fetch hosts with given params
-func (api *API) GetHost(params interface{}) (JsonResponse, error) {
-	response, err := api.Request("host.get", params)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-	return response, nil
-}
-
-// GetHistory fetch history with given params
-func (api *API) GetHistory(params interface{}) (JsonResponse, error) {
-	response, err := api.Request("history.get", params)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-	return response, nil
-}
-
-// ItemUpdate updates items with given params
-func (api *API) ItemUpdate(params interface{}) (JsonResponse, error) {
-	response, err := api.Request("item.update", params)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-	return response, nil
-}
-
-// DiscoveryRuleUpdate updates drules with given params
-func (api *API) DiscoveryRuleUpdate(params interface{}) (JsonResponse, error) {
-	response, err := api.Request("discoveryrule.update", params)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-	return response, nil
-}
-
-// Request makes request to Zabbix server with given params
-func (api *API) Request(method string, params interface{}) (JsonResponse, error) {
-	var jsonReq JsonRequest
-	jsonReq.Jsonrpc = "2.0"
-	jsonReq.Method = method
-	jsonReq.Auth = api.auth
-	jsonReq.Params = params
-	api.id++
-	jsonReq.ID = api.id
-
-	encodedReq, err := json.Marshal(jsonReq)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-
-	request, err := http.NewRequest("POST", api.url, bytes.NewBuffer(encodedReq))
-	if err != nil {
-		return JsonResponse{}, err
-	}
-	request.Header.Add("Content-type", "application/json-rpc")
-
-	response, err := api.httpClient.Do(request)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-	defer response.Body.Close()
-
-	var resultResponse JsonResponse
-	var buf bytes.Buffer
-	_, err = io.Copy(&buf, response.Body)
-	if err != nil {
-		return JsonResponse{}, err
-	}
-
-	json.Unmarshal(buf.Bytes(), &resultResponse)
-
-	return resultResponse, nil
-}
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type API struct {
+	url        string
+	user       string
+	pass       string
+	id         atomic.Int64
+	httpClient *http.Client
+	opts       ClientOptions
+
+	// stateMu guards every field below, since auth can be read from
+	// RequestContext/doRequest and written from login()/reLogin() on a
+	// different goroutine when several goroutines share one API.
+	stateMu       sync.RWMutex
+	auth          string
+	loggedin      bool
+	version       string
+	parsedVersion zbxVersion
+	useAuthHeader bool
+
+	// loginMu serializes actual user.login calls so that when several
+	// goroutines hit an expired session at once, only one of them
+	// re-authenticates.
+	loginMu sync.Mutex
+}
+
+// authSnapshot returns the auth token and transport mode to use for the
+// next request.
+func (api *API) authSnapshot() (token string, useHeader bool) {
+	api.stateMu.RLock()
+	defer api.stateMu.RUnlock()
+	return api.auth, api.useAuthHeader
+}
+
+// setAuth records a newly (re-)established session.
+func (api *API) setAuth(token string) {
+	api.stateMu.Lock()
+	defer api.stateMu.Unlock()
+	api.auth = token
+	api.loggedin = true
+}
+
+type JsonRequest struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+	Auth    string      `json:"auth,omitempty"`
+	ID      int         `json:"id"`
+}
+
+type JsonResponse struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Result  interface{} `json:"result"`
+	Error   ZbxError    `json:"error"`
+	ID      int         `json:"id"`
+}
+
+type ZbxError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+func (z *ZbxError) Error() string {
+	return fmt.Sprintf("Error code: %d, message: %s, data: %s", z.Code, z.Message, z.Data)
+}
+
+// NewClient creates a new client. opts configures the HTTP client, request
+// timeout, retry/backoff behaviour, rate limiting and TLS settings; the
+// zero value of ClientOptions gives a plain client with no retries and no
+// rate limiting, matching the previous behaviour.
+func NewClient(url, user, pass string, opts ClientOptions) (*API, error) {
+	if url == "" || user == "" || pass == "" {
+		return nil, errors.New("you have to provide url, user name and password")
+	}
+
+	httpClient, err := opts.buildHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &API{
+		url:        url,
+		user:       user,
+		pass:       pass,
+		httpClient: httpClient,
+		opts:       opts.withDefaults(),
+	}, nil
+}
+
+// Login user in Zabbix. If opts.SessionStore is set, Login first tries to
+// resume a cached session (validated with a cheap
+// user.checkAuthentication call) before falling back to user.login, so a
+// process restarted frequently doesn't burn a new row in Zabbix's
+// sessions table every time.
+func (api *API) Login() (bool, error) {
+	api.loginMu.Lock()
+	defer api.loginMu.Unlock()
+	return api.login(true)
+}
+
+// login performs the actual login, optionally trying the cached session
+// first. The parameter holding the account name changed from "user" to
+// "username" in Zabbix 5.4, so it detects the server version first (via
+// Version) and picks the right one automatically. Callers must hold
+// loginMu.
+func (api *API) login(useCache bool) (bool, error) {
+	if useCache && api.opts.SessionStore != nil {
+		if token, ok := api.opts.SessionStore.Load(api.user); ok && api.checkAuthentication(token) {
+			api.setAuth(token)
+			return true, nil
+		}
+	}
+
+	userParam := "user"
+	if _, err := api.Version(); err == nil && api.parsedVersionAtLeast(5, 4) {
+		userParam = "username"
+	}
+
+	params := make(map[string]string)
+	params[userParam] = api.user
+	params["password"] = api.pass
+
+	response, err := api.Request("user.login", params)
+	if err != nil {
+		return false, err
+	}
+
+	// check response error code
+	if response.Error.Code != 0 {
+		return false, &response.Error
+	}
+
+	token := response.Result.(string)
+	api.setAuth(token)
+
+	if api.opts.SessionStore != nil {
+		if err := api.opts.SessionStore.Save(api.user, token); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// checkAuthentication reports whether token is still a valid session by
+// making a cheap user.checkAuthentication call. It never triggers
+// reLogin itself (see RequestContext), so it can't recurse.
+func (api *API) checkAuthentication(token string) bool {
+	err := api.call("user.checkAuthentication", map[string]string{"sessionid": token}, nil)
+	return err == nil
+}
+
+// reLogin re-authenticates after a "session terminated" error observed
+// with observedAuth as the token in use at the time, skipping the
+// session cache since the cached token is the one that just failed.
+// It's guarded by loginMu so that when several goroutines share one API
+// and hit the same expired session, only the first actually calls
+// user.login; the rest notice the token has already moved on from
+// observedAuth and return immediately.
+func (api *API) reLogin(observedAuth string) error {
+	api.loginMu.Lock()
+	defer api.loginMu.Unlock()
+
+	if current, _ := api.authSnapshot(); current != observedAuth {
+		return nil
+	}
+
+	_, err := api.login(false)
+	return err
+}
+
+// isSessionExpiredError reports whether e is Zabbix's "session
+// terminated" error, returned when the cached auth token has been
+// invalidated (e.g. the server restarted or another client logged out).
+func isSessionExpiredError(e *ZbxError) bool {
+	return e.Code == -32602 && strings.Contains(strings.ToLower(e.Data), "session terminated")
+}
+
+// Logout user
+func (api *API) Logout() (bool, error) {
+	emptyParams := make(map[string]string)
+
+	response, err := api.Request("user.logout", emptyParams)
+	if err != nil {
+		return false, err
+	}
+
+	// check response error code
+	if response.Error.Code != 0 {
+		return false, &response.Error
+	}
+
+	api.stateMu.Lock()
+	api.loggedin = false
+	api.auth = ""
+	api.stateMu.Unlock()
+
+	if api.opts.SessionStore != nil {
+		api.opts.SessionStore.Delete(api.user)
+	}
+	return true, nil
+}
+
+// Request makes a request to the Zabbix server with given params using a
+// background context. See RequestContext for cancellation, per-call
+// timeouts, retries and rate limiting.
+func (api *API) Request(method string, params interface{}) (JsonResponse, error) {
+	return api.RequestContext(context.Background(), method, params)
+}
+
+// RequestContext makes a request to the Zabbix server with given params,
+// honouring ctx for cancellation and deadlines. It applies the API's
+// configured rate limiter before each attempt and retries transient
+// failures (network errors, HTTP 5xx responses and Zabbix's temporary
+// error codes) with exponential backoff, up to opts.MaxRetries times.
+func (api *API) RequestContext(ctx context.Context, method string, params interface{}) (JsonResponse, error) {
+	if api.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.opts.Timeout)
+		defer cancel()
+	}
+
+	reqID := int(api.id.Add(1))
+
+	var lastErr error
+	reLoggedIn := false
+	for attempt := 0; attempt <= api.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := api.opts.Backoff.Wait(ctx, attempt); err != nil {
+				return JsonResponse{}, err
+			}
+		}
+
+		if api.opts.RateLimit != nil {
+			if err := api.opts.RateLimit.Wait(ctx); err != nil {
+				return JsonResponse{}, err
+			}
+		}
+
+		authToken, useAuthHeader := api.authSnapshot()
+
+		var jsonReq JsonRequest
+		jsonReq.Jsonrpc = "2.0"
+		jsonReq.Method = method
+		jsonReq.Params = params
+		jsonReq.ID = reqID
+		if !useAuthHeader {
+			jsonReq.Auth = authToken
+		}
+
+		encodedReq, err := json.Marshal(jsonReq)
+		if err != nil {
+			return JsonResponse{}, err
+		}
+
+		resultResponse, retryable, reqErr := api.doRequest(ctx, authToken, useAuthHeader, encodedReq)
+
+		if reqErr == nil && method != "user.login" && method != "user.checkAuthentication" &&
+			!reLoggedIn && isSessionExpiredError(&resultResponse.Error) {
+			reLoggedIn = true
+			if err := api.reLogin(authToken); err == nil {
+				attempt--
+				continue
+			}
+		}
+
+		if reqErr == nil && !retryable {
+			return resultResponse, nil
+		}
+		if !retryable {
+			return resultResponse, reqErr
+		}
+
+		lastErr = reqErr
+		if lastErr == nil {
+			lastErr = &resultResponse.Error
+		}
+	}
+
+	return JsonResponse{}, fmt.Errorf("zabbix: request failed after %d attempts: %w", api.opts.MaxRetries+1, lastErr)
+}
+
+// doRequest performs a single HTTP round trip and reports whether a
+// failure (if any) looks transient and worth retrying.
+func (api *API) doRequest(ctx context.Context, authToken string, useAuthHeader bool, body []byte) (response JsonResponse, retryable bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, "POST", api.url, bytes.NewBuffer(body))
+	if err != nil {
+		return JsonResponse{}, false, err
+	}
+	request.Header.Add("Content-type", "application/json-rpc")
+	if useAuthHeader && authToken != "" {
+		request.Header.Set("Authorization", "Bearer "+authToken)
+	}
+
+	httpResp, err := api.httpClient.Do(request)
+	if err != nil {
+		return JsonResponse{}, true, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= 500 {
+		return JsonResponse{}, true, fmt.Errorf("zabbix: server returned status %d", httpResp.StatusCode)
+	}
+
+	var resultResponse JsonResponse
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, httpResp.Body); err != nil {
+		return JsonResponse{}, true, err
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &resultResponse); err != nil {
+		return JsonResponse{}, false, err
+	}
+
+	if resultResponse.Error.Code != 0 {
+		return resultResponse, isRetryableZbxError(resultResponse.Error.Code), nil
+	}
+
+	return resultResponse, false, nil
+}
+
+// isRetryableZbxError reports whether a Zabbix JSON-RPC error code
+// represents a transient condition worth retrying, such as an internal
+// or temporary database error.
+func isRetryableZbxError(code int) bool {
+	return code == -32603
+}
@@ -0,0 +1,203 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, req JsonRequest)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JsonRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		handler(w, req)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func writeJSONResponse(t *testing.T, w http.ResponseWriter, id int, result interface{}, zbxErr *ZbxError) {
+	t.Helper()
+	resp := JsonResponse{Jsonrpc: "2.0", ID: id}
+	if zbxErr != nil {
+		resp.Error = *zbxErr
+	} else {
+		resp.Result = result
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		t.Fatalf("encode response: %v", err)
+	}
+}
+
+func noBackoff() Backoff {
+	return ExponentialBackoff{Base: time.Millisecond, Max: time.Millisecond}
+}
+
+func TestRequestContextRetriesOnServerError(t *testing.T) {
+	var attempts int32
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(t, w, req.ID, "ok", nil)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{MaxRetries: 3, Backoff: noBackoff()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	resp, err := api.Request("some.method", nil)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if resp.Result != "ok" {
+		t.Fatalf("Result = %v, want ok", resp.Result)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRequestContextRetriesOnTemporaryZbxError(t *testing.T) {
+	var attempts int32
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n == 1 {
+			writeJSONResponse(t, w, req.ID, nil, &ZbxError{Code: -32603, Message: "internal error"})
+			return
+		}
+		writeJSONResponse(t, w, req.ID, "ok", nil)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{MaxRetries: 1, Backoff: noBackoff()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	resp, err := api.Request("some.method", nil)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if resp.Result != "ok" {
+		t.Fatalf("Result = %v, want ok", resp.Result)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRequestContextGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{MaxRetries: 2, Backoff: noBackoff()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := api.Request("some.method", nil); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3 (1 initial + 2 retries)", got)
+	}
+}
+
+func TestRequestContextDoesNotRetryNonRetryableError(t *testing.T) {
+	var attempts int32
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		atomic.AddInt32(&attempts, 1)
+		writeJSONResponse(t, w, req.ID, nil, &ZbxError{Code: -32500, Message: "application error"})
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{MaxRetries: 3, Backoff: noBackoff()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	resp, err := api.Request("some.method", nil)
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if resp.Error.Code != -32500 {
+		t.Fatalf("Error.Code = %d, want -32500", resp.Error.Code)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("attempts = %d, want 1 (no retry)", got)
+	}
+}
+
+// countingLimiter records how many times Wait was called before always
+// allowing the call through.
+type countingLimiter struct {
+	calls int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.calls, 1)
+	return nil
+}
+
+func TestRequestContextConsultsRateLimiterOnEveryAttempt(t *testing.T) {
+	var attempts int32
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n <= 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(t, w, req.ID, "ok", nil)
+	})
+
+	limiter := &countingLimiter{}
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{MaxRetries: 1, Backoff: noBackoff(), RateLimit: limiter})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := api.Request("some.method", nil); err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if got := atomic.LoadInt32(&limiter.calls); got != 2 {
+		t.Fatalf("rate limiter Wait calls = %d, want 2 (once per attempt)", got)
+	}
+}
+
+func TestRequestContextRateLimiterCancellation(t *testing.T) {
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		writeJSONResponse(t, w, req.ID, "ok", nil)
+	})
+
+	blocking := RateLimiter(rateLimiterFunc(func(ctx context.Context) error {
+		return ctx.Err()
+	}))
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{RateLimit: blocking})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := api.RequestContext(ctx, "some.method", nil); err == nil {
+		t.Fatal("expected the cancelled context to abort the request")
+	}
+}
+
+type rateLimiterFunc func(ctx context.Context) error
+
+func (f rateLimiterFunc) Wait(ctx context.Context) error { return f(ctx) }
@@ -0,0 +1,771 @@
+// Typed wrappers for the core Zabbix API objects, so callers get concrete
+// Go types instead of type-asserting response.Result by hand. Create and
+// Update take typed objects and Delete takes IDs; the Get methods take
+// their params untyped (usually a map[string]interface{}) and pass them
+// through unchanged.
+
+package zabbix
+
+import "encoding/json"
+
+// call invokes method with params and unmarshals the result into out. It is
+// the shared entry point used by every generated entity method so callers
+// get concrete Go types instead of interface{}. params is sent as given.
+func (api *API) call(method string, params, out interface{}) error {
+	response, err := api.Request(method, params)
+	if err != nil {
+		return err
+	}
+	if response.Error.Code != 0 {
+		return &response.Error
+	}
+	if out == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(response.Result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// idList is the shape Zabbix returns from create/update/delete calls, e.g.
+// {"hostids": ["10084"]}.
+type idList map[string][]string
+
+// Host is the Zabbix "host" object.
+type Host struct {
+	HostID     string          `json:"hostid,omitempty"`
+	Host       string          `json:"host,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Status     int             `json:"status,omitempty"`
+	Available  int             `json:"available,omitempty"`
+	Groups     []HostGroup     `json:"groups,omitempty"`
+	Interfaces []HostInterface `json:"interfaces,omitempty"`
+}
+
+// HostInterface is the Zabbix "hostinterface" object, embedded in Host.
+type HostInterface struct {
+	InterfaceID string `json:"interfaceid,omitempty"`
+	IP          string `json:"ip,omitempty"`
+	DNS         string `json:"dns,omitempty"`
+	Port        string `json:"port,omitempty"`
+	Main        int    `json:"main,omitempty"`
+	Type        int    `json:"type,omitempty"`
+	UseIP       int    `json:"useip,omitempty"`
+}
+
+// GetHosts fetches hosts matching params.
+func (api *API) GetHosts(params interface{}) ([]Host, error) {
+	var hosts []Host
+	err := api.call("host.get", params, &hosts)
+	return hosts, err
+}
+
+// CreateHosts creates hosts and returns their new host IDs.
+func (api *API) CreateHosts(hosts ...Host) ([]string, error) {
+	var result idList
+	if err := api.call("host.create", hosts, &result); err != nil {
+		return nil, err
+	}
+	return result["hostids"], nil
+}
+
+// UpdateHosts updates hosts and returns their host IDs.
+func (api *API) UpdateHosts(hosts ...Host) ([]string, error) {
+	var result idList
+	if err := api.call("host.update", hosts, &result); err != nil {
+		return nil, err
+	}
+	return result["hostids"], nil
+}
+
+// DeleteHosts deletes hosts by ID.
+func (api *API) DeleteHosts(hostIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("host.delete", hostIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["hostids"], nil
+}
+
+// HostGroup is the Zabbix "hostgroup" object.
+type HostGroup struct {
+	GroupID string `json:"groupid,omitempty"`
+	Name    string `json:"name,omitempty"`
+}
+
+// GetHostGroups fetches host groups matching params.
+func (api *API) GetHostGroups(params interface{}) ([]HostGroup, error) {
+	var groups []HostGroup
+	err := api.call("hostgroup.get", params, &groups)
+	return groups, err
+}
+
+// CreateHostGroups creates host groups and returns their new group IDs.
+func (api *API) CreateHostGroups(groups ...HostGroup) ([]string, error) {
+	var result idList
+	if err := api.call("hostgroup.create", groups, &result); err != nil {
+		return nil, err
+	}
+	return result["groupids"], nil
+}
+
+// UpdateHostGroups updates host groups and returns their group IDs.
+func (api *API) UpdateHostGroups(groups ...HostGroup) ([]string, error) {
+	var result idList
+	if err := api.call("hostgroup.update", groups, &result); err != nil {
+		return nil, err
+	}
+	return result["groupids"], nil
+}
+
+// DeleteHostGroups deletes host groups by ID.
+func (api *API) DeleteHostGroups(groupIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("hostgroup.delete", groupIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["groupids"], nil
+}
+
+// Template is the Zabbix "template" object.
+type Template struct {
+	TemplateID string      `json:"templateid,omitempty"`
+	Host       string      `json:"host,omitempty"`
+	Name       string      `json:"name,omitempty"`
+	Groups     []HostGroup `json:"groups,omitempty"`
+}
+
+// GetTemplates fetches templates matching params.
+func (api *API) GetTemplates(params interface{}) ([]Template, error) {
+	var templates []Template
+	err := api.call("template.get", params, &templates)
+	return templates, err
+}
+
+// CreateTemplates creates templates and returns their new template IDs.
+func (api *API) CreateTemplates(templates ...Template) ([]string, error) {
+	var result idList
+	if err := api.call("template.create", templates, &result); err != nil {
+		return nil, err
+	}
+	return result["templateids"], nil
+}
+
+// UpdateTemplates updates templates and returns their template IDs.
+func (api *API) UpdateTemplates(templates ...Template) ([]string, error) {
+	var result idList
+	if err := api.call("template.update", templates, &result); err != nil {
+		return nil, err
+	}
+	return result["templateids"], nil
+}
+
+// DeleteTemplates deletes templates by ID.
+func (api *API) DeleteTemplates(templateIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("template.delete", templateIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["templateids"], nil
+}
+
+// Item is the Zabbix "item" object.
+type Item struct {
+	ItemID    string `json:"itemid,omitempty"`
+	HostID    string `json:"hostid,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Key       string `json:"key_,omitempty"`
+	Type      int    `json:"type,omitempty"`
+	ValueType int    `json:"value_type,omitempty"`
+	Delay     string `json:"delay,omitempty"`
+}
+
+// GetItems fetches items matching params.
+func (api *API) GetItems(params interface{}) ([]Item, error) {
+	var items []Item
+	err := api.call("item.get", params, &items)
+	return items, err
+}
+
+// CreateItems creates items and returns their new item IDs.
+func (api *API) CreateItems(items ...Item) ([]string, error) {
+	var result idList
+	if err := api.call("item.create", items, &result); err != nil {
+		return nil, err
+	}
+	return result["itemids"], nil
+}
+
+// UpdateItems updates items and returns their item IDs.
+func (api *API) UpdateItems(items ...Item) ([]string, error) {
+	var result idList
+	if err := api.call("item.update", items, &result); err != nil {
+		return nil, err
+	}
+	return result["itemids"], nil
+}
+
+// DeleteItems deletes items by ID.
+func (api *API) DeleteItems(itemIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("item.delete", itemIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["itemids"], nil
+}
+
+// Trigger is the Zabbix "trigger" object.
+type Trigger struct {
+	TriggerID   string `json:"triggerid,omitempty"`
+	Description string `json:"description,omitempty"`
+	Expression  string `json:"expression,omitempty"`
+	Priority    int    `json:"priority,omitempty"`
+	Status      int    `json:"status,omitempty"`
+}
+
+// GetTriggers fetches triggers matching params.
+func (api *API) GetTriggers(params interface{}) ([]Trigger, error) {
+	var triggers []Trigger
+	err := api.call("trigger.get", params, &triggers)
+	return triggers, err
+}
+
+// CreateTriggers creates triggers and returns their new trigger IDs.
+func (api *API) CreateTriggers(triggers ...Trigger) ([]string, error) {
+	var result idList
+	if err := api.call("trigger.create", triggers, &result); err != nil {
+		return nil, err
+	}
+	return result["triggerids"], nil
+}
+
+// UpdateTriggers updates triggers and returns their trigger IDs.
+func (api *API) UpdateTriggers(triggers ...Trigger) ([]string, error) {
+	var result idList
+	if err := api.call("trigger.update", triggers, &result); err != nil {
+		return nil, err
+	}
+	return result["triggerids"], nil
+}
+
+// DeleteTriggers deletes triggers by ID.
+func (api *API) DeleteTriggers(triggerIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("trigger.delete", triggerIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["triggerids"], nil
+}
+
+// Graph is the Zabbix "graph" object.
+type Graph struct {
+	GraphID string `json:"graphid,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+}
+
+// GetGraphs fetches graphs matching params.
+func (api *API) GetGraphs(params interface{}) ([]Graph, error) {
+	var graphs []Graph
+	err := api.call("graph.get", params, &graphs)
+	return graphs, err
+}
+
+// CreateGraphs creates graphs and returns their new graph IDs.
+func (api *API) CreateGraphs(graphs ...Graph) ([]string, error) {
+	var result idList
+	if err := api.call("graph.create", graphs, &result); err != nil {
+		return nil, err
+	}
+	return result["graphids"], nil
+}
+
+// UpdateGraphs updates graphs and returns their graph IDs.
+func (api *API) UpdateGraphs(graphs ...Graph) ([]string, error) {
+	var result idList
+	if err := api.call("graph.update", graphs, &result); err != nil {
+		return nil, err
+	}
+	return result["graphids"], nil
+}
+
+// DeleteGraphs deletes graphs by ID.
+func (api *API) DeleteGraphs(graphIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("graph.delete", graphIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["graphids"], nil
+}
+
+// History is the Zabbix "history" object, one value of an item's history.
+type History struct {
+	ItemID string `json:"itemid,omitempty"`
+	Clock  string `json:"clock,omitempty"`
+	Value  string `json:"value,omitempty"`
+	NS     string `json:"ns,omitempty"`
+}
+
+// GetHistories fetches history values matching params. History has no
+// create/update/delete counterpart in the Zabbix API; values are written
+// via the trapper protocol (see Sender) or by the data collector.
+func (api *API) GetHistories(params interface{}) ([]History, error) {
+	var history []History
+	err := api.call("history.get", params, &history)
+	return history, err
+}
+
+// Event is the Zabbix "event" object.
+type Event struct {
+	EventID  string `json:"eventid,omitempty"`
+	Source   int    `json:"source,omitempty"`
+	Object   int    `json:"object,omitempty"`
+	ObjectID string `json:"objectid,omitempty"`
+	Clock    string `json:"clock,omitempty"`
+	Value    int    `json:"value,omitempty"`
+}
+
+// GetEvents fetches events matching params.
+func (api *API) GetEvents(params interface{}) ([]Event, error) {
+	var events []Event
+	err := api.call("event.get", params, &events)
+	return events, err
+}
+
+// AcknowledgeEvents acknowledges events and returns their event IDs. It
+// maps to event.acknowledge, the closest Zabbix has to an "update" for
+// events.
+func (api *API) AcknowledgeEvents(params interface{}) ([]string, error) {
+	var result idList
+	if err := api.call("event.acknowledge", params, &result); err != nil {
+		return nil, err
+	}
+	return result["eventids"], nil
+}
+
+// Problem is the Zabbix "problem" object.
+type Problem struct {
+	EventID  string `json:"eventid,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Severity int    `json:"severity,omitempty"`
+	Clock    string `json:"clock,omitempty"`
+}
+
+// GetProblems fetches problems matching params. Problems are read-only
+// from the API's point of view; use AcknowledgeEvents to acknowledge or
+// close them.
+func (api *API) GetProblems(params interface{}) ([]Problem, error) {
+	var problems []Problem
+	err := api.call("problem.get", params, &problems)
+	return problems, err
+}
+
+// Maintenance is the Zabbix "maintenance" object.
+type Maintenance struct {
+	MaintenanceID string      `json:"maintenanceid,omitempty"`
+	Name          string      `json:"name,omitempty"`
+	ActiveSince   string      `json:"active_since,omitempty"`
+	ActiveTill    string      `json:"active_till,omitempty"`
+	Groups        []HostGroup `json:"groups,omitempty"`
+	Hosts         []Host      `json:"hosts,omitempty"`
+}
+
+// GetMaintenances fetches maintenance windows matching params.
+func (api *API) GetMaintenances(params interface{}) ([]Maintenance, error) {
+	var maintenances []Maintenance
+	err := api.call("maintenance.get", params, &maintenances)
+	return maintenances, err
+}
+
+// CreateMaintenances creates maintenance windows and returns their IDs.
+func (api *API) CreateMaintenances(maintenances ...Maintenance) ([]string, error) {
+	var result idList
+	if err := api.call("maintenance.create", maintenances, &result); err != nil {
+		return nil, err
+	}
+	return result["maintenanceids"], nil
+}
+
+// UpdateMaintenances updates maintenance windows and returns their IDs.
+func (api *API) UpdateMaintenances(maintenances ...Maintenance) ([]string, error) {
+	var result idList
+	if err := api.call("maintenance.update", maintenances, &result); err != nil {
+		return nil, err
+	}
+	return result["maintenanceids"], nil
+}
+
+// DeleteMaintenances deletes maintenance windows by ID.
+func (api *API) DeleteMaintenances(maintenanceIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("maintenance.delete", maintenanceIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["maintenanceids"], nil
+}
+
+// User is the Zabbix "user" object.
+type User struct {
+	UserID   string `json:"userid,omitempty"`
+	Username string `json:"username,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Surname  string `json:"surname,omitempty"`
+}
+
+// GetUsers fetches users matching params.
+func (api *API) GetUsers(params interface{}) ([]User, error) {
+	var users []User
+	err := api.call("user.get", params, &users)
+	return users, err
+}
+
+// CreateUsers creates users and returns their new user IDs.
+func (api *API) CreateUsers(users ...User) ([]string, error) {
+	var result idList
+	if err := api.call("user.create", users, &result); err != nil {
+		return nil, err
+	}
+	return result["userids"], nil
+}
+
+// UpdateUsers updates users and returns their user IDs.
+func (api *API) UpdateUsers(users ...User) ([]string, error) {
+	var result idList
+	if err := api.call("user.update", users, &result); err != nil {
+		return nil, err
+	}
+	return result["userids"], nil
+}
+
+// DeleteUsers deletes users by ID.
+func (api *API) DeleteUsers(userIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("user.delete", userIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["userids"], nil
+}
+
+// UserGroup is the Zabbix "usergroup" object.
+type UserGroup struct {
+	UsrGrpID string `json:"usrgrpid,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// GetUserGroups fetches user groups matching params.
+func (api *API) GetUserGroups(params interface{}) ([]UserGroup, error) {
+	var groups []UserGroup
+	err := api.call("usergroup.get", params, &groups)
+	return groups, err
+}
+
+// CreateUserGroups creates user groups and returns their new group IDs.
+func (api *API) CreateUserGroups(groups ...UserGroup) ([]string, error) {
+	var result idList
+	if err := api.call("usergroup.create", groups, &result); err != nil {
+		return nil, err
+	}
+	return result["usrgrpids"], nil
+}
+
+// UpdateUserGroups updates user groups and returns their group IDs.
+func (api *API) UpdateUserGroups(groups ...UserGroup) ([]string, error) {
+	var result idList
+	if err := api.call("usergroup.update", groups, &result); err != nil {
+		return nil, err
+	}
+	return result["usrgrpids"], nil
+}
+
+// DeleteUserGroups deletes user groups by ID.
+func (api *API) DeleteUserGroups(usrgrpIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("usergroup.delete", usrgrpIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["usrgrpids"], nil
+}
+
+// Action is the Zabbix "action" object.
+type Action struct {
+	ActionID    string `json:"actionid,omitempty"`
+	Name        string `json:"name,omitempty"`
+	EventSource int    `json:"eventsource,omitempty"`
+	Status      int    `json:"status,omitempty"`
+}
+
+// GetActions fetches actions matching params.
+func (api *API) GetActions(params interface{}) ([]Action, error) {
+	var actions []Action
+	err := api.call("action.get", params, &actions)
+	return actions, err
+}
+
+// CreateActions creates actions and returns their new action IDs.
+func (api *API) CreateActions(actions ...Action) ([]string, error) {
+	var result idList
+	if err := api.call("action.create", actions, &result); err != nil {
+		return nil, err
+	}
+	return result["actionids"], nil
+}
+
+// UpdateActions updates actions and returns their action IDs.
+func (api *API) UpdateActions(actions ...Action) ([]string, error) {
+	var result idList
+	if err := api.call("action.update", actions, &result); err != nil {
+		return nil, err
+	}
+	return result["actionids"], nil
+}
+
+// DeleteActions deletes actions by ID.
+func (api *API) DeleteActions(actionIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("action.delete", actionIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["actionids"], nil
+}
+
+// Media is the Zabbix "media" object (a user's notification channel).
+type Media struct {
+	MediaID     string `json:"mediaid,omitempty"`
+	MediaTypeID string `json:"mediatypeid,omitempty"`
+	UserID      string `json:"userid,omitempty"`
+	SendTo      string `json:"sendto,omitempty"`
+	Active      int    `json:"active,omitempty"`
+}
+
+// GetMedia fetches user media matching params. Zabbix has no standalone
+// media.get method: user.get only nests media under each user's "medias"
+// array, and only when params requests selectMedias, so callers must set
+// that selector. GetMedia flattens the per-user arrays into a single
+// slice.
+func (api *API) GetMedia(params interface{}) ([]Media, error) {
+	var users []struct {
+		UserID string  `json:"userid"`
+		Medias []Media `json:"medias"`
+	}
+	if err := api.call("user.get", params, &users); err != nil {
+		return nil, err
+	}
+
+	var media []Media
+	for _, user := range users {
+		media = append(media, user.Medias...)
+	}
+	return media, nil
+}
+
+// Proxy is the Zabbix "proxy" object.
+type Proxy struct {
+	ProxyID string `json:"proxyid,omitempty"`
+	Host    string `json:"host,omitempty"`
+	Status  int    `json:"status,omitempty"`
+}
+
+// GetProxies fetches proxies matching params.
+func (api *API) GetProxies(params interface{}) ([]Proxy, error) {
+	var proxies []Proxy
+	err := api.call("proxy.get", params, &proxies)
+	return proxies, err
+}
+
+// CreateProxies creates proxies and returns their new proxy IDs.
+func (api *API) CreateProxies(proxies ...Proxy) ([]string, error) {
+	var result idList
+	if err := api.call("proxy.create", proxies, &result); err != nil {
+		return nil, err
+	}
+	return result["proxyids"], nil
+}
+
+// UpdateProxies updates proxies and returns their proxy IDs.
+func (api *API) UpdateProxies(proxies ...Proxy) ([]string, error) {
+	var result idList
+	if err := api.call("proxy.update", proxies, &result); err != nil {
+		return nil, err
+	}
+	return result["proxyids"], nil
+}
+
+// DeleteProxies deletes proxies by ID.
+func (api *API) DeleteProxies(proxyIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("proxy.delete", proxyIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["proxyids"], nil
+}
+
+// Service is the Zabbix "service" object.
+type Service struct {
+	ServiceID string `json:"serviceid,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Algorithm int    `json:"algorithm,omitempty"`
+}
+
+// GetServices fetches services matching params.
+func (api *API) GetServices(params interface{}) ([]Service, error) {
+	var services []Service
+	err := api.call("service.get", params, &services)
+	return services, err
+}
+
+// CreateServices creates services and returns their new service IDs.
+func (api *API) CreateServices(services ...Service) ([]string, error) {
+	var result idList
+	if err := api.call("service.create", services, &result); err != nil {
+		return nil, err
+	}
+	return result["serviceids"], nil
+}
+
+// UpdateServices updates services and returns their service IDs.
+func (api *API) UpdateServices(services ...Service) ([]string, error) {
+	var result idList
+	if err := api.call("service.update", services, &result); err != nil {
+		return nil, err
+	}
+	return result["serviceids"], nil
+}
+
+// DeleteServices deletes services by ID.
+func (api *API) DeleteServices(serviceIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("service.delete", serviceIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["serviceids"], nil
+}
+
+// Map is the Zabbix "map" object (network map).
+type Map struct {
+	SysMapID string `json:"sysmapid,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// GetMaps fetches network maps matching params.
+func (api *API) GetMaps(params interface{}) ([]Map, error) {
+	var maps []Map
+	err := api.call("map.get", params, &maps)
+	return maps, err
+}
+
+// CreateMaps creates network maps and returns their new map IDs.
+func (api *API) CreateMaps(maps ...Map) ([]string, error) {
+	var result idList
+	if err := api.call("map.create", maps, &result); err != nil {
+		return nil, err
+	}
+	return result["sysmapids"], nil
+}
+
+// UpdateMaps updates network maps and returns their map IDs.
+func (api *API) UpdateMaps(maps ...Map) ([]string, error) {
+	var result idList
+	if err := api.call("map.update", maps, &result); err != nil {
+		return nil, err
+	}
+	return result["sysmapids"], nil
+}
+
+// DeleteMaps deletes network maps by ID.
+func (api *API) DeleteMaps(sysmapIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("map.delete", sysmapIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["sysmapids"], nil
+}
+
+// DiscoveryRule is the Zabbix "drule" (network discovery rule) object.
+type DiscoveryRule struct {
+	DRuleID string `json:"druleid,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Iprange string `json:"iprange,omitempty"`
+	Status  int    `json:"status,omitempty"`
+}
+
+// GetDiscoveryRules fetches network discovery rules matching params.
+func (api *API) GetDiscoveryRules(params interface{}) ([]DiscoveryRule, error) {
+	var drules []DiscoveryRule
+	err := api.call("drule.get", params, &drules)
+	return drules, err
+}
+
+// CreateDiscoveryRules creates discovery rules and returns their new IDs.
+func (api *API) CreateDiscoveryRules(drules ...DiscoveryRule) ([]string, error) {
+	var result idList
+	if err := api.call("drule.create", drules, &result); err != nil {
+		return nil, err
+	}
+	return result["druleids"], nil
+}
+
+// UpdateDiscoveryRules updates discovery rules and returns their IDs.
+func (api *API) UpdateDiscoveryRules(drules ...DiscoveryRule) ([]string, error) {
+	var result idList
+	if err := api.call("drule.update", drules, &result); err != nil {
+		return nil, err
+	}
+	return result["druleids"], nil
+}
+
+// DeleteDiscoveryRules deletes discovery rules by ID.
+func (api *API) DeleteDiscoveryRules(druleIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("drule.delete", druleIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["druleids"], nil
+}
+
+// Script is the Zabbix "script" object.
+type Script struct {
+	ScriptID string `json:"scriptid,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Command  string `json:"command,omitempty"`
+	Type     int    `json:"type,omitempty"`
+}
+
+// GetScripts fetches scripts matching params.
+func (api *API) GetScripts(params interface{}) ([]Script, error) {
+	var scripts []Script
+	err := api.call("script.get", params, &scripts)
+	return scripts, err
+}
+
+// CreateScripts creates scripts and returns their new script IDs.
+func (api *API) CreateScripts(scripts ...Script) ([]string, error) {
+	var result idList
+	if err := api.call("script.create", scripts, &result); err != nil {
+		return nil, err
+	}
+	return result["scriptids"], nil
+}
+
+// UpdateScripts updates scripts and returns their script IDs.
+func (api *API) UpdateScripts(scripts ...Script) ([]string, error) {
+	var result idList
+	if err := api.call("script.update", scripts, &result); err != nil {
+		return nil, err
+	}
+	return result["scriptids"], nil
+}
+
+// DeleteScripts deletes scripts by ID.
+func (api *API) DeleteScripts(scriptIDs ...string) ([]string, error) {
+	var result idList
+	if err := api.call("script.delete", scriptIDs, &result); err != nil {
+		return nil, err
+	}
+	return result["scriptids"], nil
+}
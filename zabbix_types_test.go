@@ -0,0 +1,107 @@
+package zabbix
+
+import (
+	"net/http"
+	"testing"
+)
+
+// TestGetMediaFlattensUserMedias guards against the user.get/media.get
+// confusion that made an earlier version of GetMedia return empty Media
+// values: Zabbix has no media.get, so GetMedia must unmarshal user.get's
+// per-user "medias" arrays and flatten them into one slice.
+func TestGetMediaFlattensUserMedias(t *testing.T) {
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		if req.Method != "user.get" {
+			t.Fatalf("unexpected method %q, want user.get", req.Method)
+		}
+		writeJSONResponse(t, w, req.ID, []map[string]interface{}{
+			{
+				"userid": "1",
+				"medias": []map[string]interface{}{
+					{"mediaid": "10", "mediatypeid": "1", "userid": "1", "sendto": "a@example.com", "active": 0},
+				},
+			},
+			{
+				"userid": "2",
+				"medias": []map[string]interface{}{
+					{"mediaid": "11", "mediatypeid": "1", "userid": "2", "sendto": "b@example.com", "active": 0},
+					{"mediaid": "12", "mediatypeid": "2", "userid": "2", "sendto": "b@example.com", "active": 1},
+				},
+			},
+		}, nil)
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	media, err := api.GetMedia(map[string]interface{}{"selectMedias": "extend"})
+	if err != nil {
+		t.Fatalf("GetMedia: %v", err)
+	}
+
+	if len(media) != 3 {
+		t.Fatalf("len(media) = %d, want 3", len(media))
+	}
+	if media[0].MediaID != "10" || media[1].MediaID != "11" || media[2].MediaID != "12" {
+		t.Fatalf("unexpected media IDs: %+v", media)
+	}
+}
+
+// TestHostGetCreateUpdateDeleteRoundTrip exercises one representative
+// entity's Get/Create/Update/Delete methods through call(), standing in
+// for the rest of the entity surface, which all share the same pattern.
+func TestHostGetCreateUpdateDeleteRoundTrip(t *testing.T) {
+	srv := newTestServer(t, func(w http.ResponseWriter, req JsonRequest) {
+		switch req.Method {
+		case "host.get":
+			writeJSONResponse(t, w, req.ID, []Host{{HostID: "10084", Host: "server1"}}, nil)
+		case "host.create":
+			writeJSONResponse(t, w, req.ID, map[string][]string{"hostids": {"10084"}}, nil)
+		case "host.update":
+			writeJSONResponse(t, w, req.ID, map[string][]string{"hostids": {"10084"}}, nil)
+		case "host.delete":
+			writeJSONResponse(t, w, req.ID, map[string][]string{"hostids": {"10084"}}, nil)
+		default:
+			t.Fatalf("unexpected method %q", req.Method)
+		}
+	})
+
+	api, err := NewClient(srv.URL, "user", "pass", ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	hosts, err := api.GetHosts(map[string]interface{}{"output": "extend"})
+	if err != nil {
+		t.Fatalf("GetHosts: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0].HostID != "10084" {
+		t.Fatalf("unexpected hosts: %+v", hosts)
+	}
+
+	createdIDs, err := api.CreateHosts(Host{Host: "server1"})
+	if err != nil {
+		t.Fatalf("CreateHosts: %v", err)
+	}
+	if len(createdIDs) != 1 || createdIDs[0] != "10084" {
+		t.Fatalf("unexpected created IDs: %v", createdIDs)
+	}
+
+	updatedIDs, err := api.UpdateHosts(Host{HostID: "10084", Status: 1})
+	if err != nil {
+		t.Fatalf("UpdateHosts: %v", err)
+	}
+	if len(updatedIDs) != 1 || updatedIDs[0] != "10084" {
+		t.Fatalf("unexpected updated IDs: %v", updatedIDs)
+	}
+
+	deletedIDs, err := api.DeleteHosts("10084")
+	if err != nil {
+		t.Fatalf("DeleteHosts: %v", err)
+	}
+	if len(deletedIDs) != 1 || deletedIDs[0] != "10084" {
+		t.Fatalf("unexpected deleted IDs: %v", deletedIDs)
+	}
+}